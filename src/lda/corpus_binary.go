@@ -0,0 +1,283 @@
+package lda
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// Binary corpus format:
+//
+//	magic       uint32 = binaryCorpusMagic
+//	version     uint32 = binaryCorpusVersion
+//	num_topics  uint32
+//	num_symbols uvarint
+//	symbols     num_symbols * (uvarint len, len bytes of UTF-8)
+//	num_docs    uvarint
+//	docs        num_docs * document, where document is:
+//	  num_words       uvarint (len(unique_words))
+//	  num_wordtopics  uvarint (len(wordtopics), also len(wordtopics_indices))
+//	  unique_words    num_words     * uvarint symbol id
+//	  wordtopics_idx  num_wordtopics * uvarint
+//	  wordtopics      num_wordtopics * uvarint
+//	  topic_histogram num_topics    * uvarint
+//
+// This avoids re-tokenizing and re-sorting text on every run: once a
+// Corpus has been trained on, SaveCorpusBinary/LoadCorpusBinary round-trip
+// it in a form that's just a handful of varint-decoded slices to rebuild.
+const (
+	binaryCorpusMagic   uint32 = 0x4c444143 // "LDAC"
+	binaryCorpusVersion uint32 = 1
+)
+
+// SaveCorpusBinary writes c to path in the binary corpus format. All
+// documents in c must share the same topic_histogram length (i.e. were
+// built with the same num_topics), since that length is stored once in
+// the header.
+func SaveCorpusBinary(path string, c *Corpus) error {
+	numTopics, err := corpusNumTopics(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New("Cannot create file: " + path)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	scratch := make([]byte, binary.MaxVarintLen64)
+
+	writeUint32 := func(v uint32) error {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], v)
+		_, err := w.Write(b[:])
+		return err
+	}
+	writeUvarint := func(v uint64) error {
+		n := binary.PutUvarint(scratch, v)
+		_, err := w.Write(scratch[:n])
+		return err
+	}
+
+	if err := writeUint32(binaryCorpusMagic); err != nil {
+		return err
+	}
+	if err := writeUint32(binaryCorpusVersion); err != nil {
+		return err
+	}
+	if err := writeUint32(uint32(numTopics)); err != nil {
+		return err
+	}
+
+	symbols, symbolIDs := buildCorpusSymbolTable(c)
+	if err := writeUvarint(uint64(len(symbols))); err != nil {
+		return err
+	}
+	for _, s := range symbols {
+		if err := writeUvarint(uint64(len(s))); err != nil {
+			return err
+		}
+		if _, err := w.WriteString(s); err != nil {
+			return err
+		}
+	}
+
+	if err := writeUvarint(uint64(len(*c))); err != nil {
+		return err
+	}
+	for _, doc := range *c {
+		if err := writeUvarint(uint64(len(doc.unique_words))); err != nil {
+			return err
+		}
+		if err := writeUvarint(uint64(len(doc.wordtopics))); err != nil {
+			return err
+		}
+		for _, word := range doc.unique_words {
+			if err := writeUvarint(uint64(symbolIDs[word])); err != nil {
+				return err
+			}
+		}
+		for _, idx := range doc.wordtopics_indices {
+			if err := writeUvarint(uint64(idx)); err != nil {
+				return err
+			}
+		}
+		for _, topic := range doc.wordtopics {
+			if err := writeUvarint(uint64(topic)); err != nil {
+				return err
+			}
+		}
+		for _, count := range doc.topic_histogram {
+			if err := writeUvarint(uint64(count)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+// LoadCorpusBinary reads a Corpus previously written by SaveCorpusBinary.
+func LoadCorpusBinary(path string) (*Corpus, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New("Cannot open file: " + path)
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	numTopics, symbols, err := readCorpusBinaryHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	numDocs, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errors.New("Error reading corpus: " + err.Error())
+	}
+
+	corpus := NewCorpus()
+	for i := uint64(0); i < numDocs; i++ {
+		doc, err := readCorpusBinaryDocument(r, numTopics, symbols)
+		if err != nil {
+			return nil, err
+		}
+		*corpus = append(*corpus, doc)
+	}
+	return corpus, nil
+}
+
+func readCorpusBinaryHeader(r *bufio.Reader) (numTopics int, symbols []string, err error) {
+	readUint32 := func() (uint32, error) {
+		var b [4]byte
+		for i := range b {
+			c, err := r.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			b[i] = c
+		}
+		return binary.BigEndian.Uint32(b[:]), nil
+	}
+
+	magic, err := readUint32()
+	if err != nil {
+		return 0, nil, errors.New("Error reading corpus header: " + err.Error())
+	}
+	if magic != binaryCorpusMagic {
+		return 0, nil, errors.New("Not a binary corpus file (bad magic)")
+	}
+	version, err := readUint32()
+	if err != nil {
+		return 0, nil, errors.New("Error reading corpus header: " + err.Error())
+	}
+	if version != binaryCorpusVersion {
+		return 0, nil, errors.New("Unsupported binary corpus version")
+	}
+	numTopicsU32, err := readUint32()
+	if err != nil {
+		return 0, nil, errors.New("Error reading corpus header: " + err.Error())
+	}
+	numTopics = int(numTopicsU32)
+
+	numSymbols, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, nil, errors.New("Error reading symbol table: " + err.Error())
+	}
+	symbols = make([]string, numSymbols)
+	for i := range symbols {
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return 0, nil, errors.New("Error reading symbol table: " + err.Error())
+		}
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return 0, nil, errors.New("Error reading symbol table: " + err.Error())
+		}
+		symbols[i] = string(buf)
+	}
+	return numTopics, symbols, nil
+}
+
+func readCorpusBinaryDocument(r *bufio.Reader, numTopics int, symbols []string) (*Document, error) {
+	numWords, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errors.New("Error reading document: " + err.Error())
+	}
+	numWordTopics, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errors.New("Error reading document: " + err.Error())
+	}
+
+	doc := new(Document)
+	doc.unique_words = make([]string, numWords)
+	for i := range doc.unique_words {
+		id, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errors.New("Error reading document: " + err.Error())
+		}
+		if int(id) >= len(symbols) {
+			return nil, errors.New("Corpus binary: symbol id out of range")
+		}
+		doc.unique_words[i] = symbols[id]
+	}
+
+	doc.wordtopics_indices = make([]int, numWordTopics)
+	for i := range doc.wordtopics_indices {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errors.New("Error reading document: " + err.Error())
+		}
+		doc.wordtopics_indices[i] = int(v)
+	}
+
+	doc.wordtopics = make([]int, numWordTopics)
+	for i := range doc.wordtopics {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errors.New("Error reading document: " + err.Error())
+		}
+		doc.wordtopics[i] = int(v)
+	}
+
+	doc.topic_histogram = make(Histogram, numTopics)
+	for i := range doc.topic_histogram {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errors.New("Error reading document: " + err.Error())
+		}
+		doc.topic_histogram[i] = int(v)
+	}
+
+	if !doc.IsValid() {
+		return nil, errors.New("Corpus binary: decoded an invalid Document")
+	}
+	return doc, nil
+}
+
+func corpusNumTopics(c *Corpus) (int, error) {
+	if len(*c) == 0 {
+		return 0, errors.New("SaveCorpusBinary requires a non-empty Corpus")
+	}
+	return len((*c)[0].topic_histogram), nil
+}
+
+// buildCorpusSymbolTable interns every unique word across c's documents,
+// returning the symbol table in a stable order and a lookup from word to
+// its id in that table.
+func buildCorpusSymbolTable(c *Corpus) (symbols []string, ids map[string]int) {
+	ids = make(map[string]int)
+	for _, doc := range *c {
+		for _, word := range doc.unique_words {
+			if _, ok := ids[word]; !ok {
+				ids[word] = len(symbols)
+				symbols = append(symbols, word)
+			}
+		}
+	}
+	return symbols, ids
+}
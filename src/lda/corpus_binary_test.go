@@ -0,0 +1,72 @@
+package lda
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func buildBinaryTestCorpus(t *testing.T) *Corpus {
+	t.Helper()
+	texts := []string{
+		"apple banana cherry date apple banana",
+		"banana cherry elderberry fig grape banana",
+		"apple fig grape date date",
+	}
+	return buildTestCorpus(t, texts, 2)
+}
+
+func assertCorporaEqual(t *testing.T, got, want *Corpus) {
+	t.Helper()
+	if len(*got) != len(*want) {
+		t.Fatalf("got %d documents, want %d", len(*got), len(*want))
+	}
+	for i := range *want {
+		g, w := (*got)[i], (*want)[i]
+		if !reflect.DeepEqual(g.unique_words, w.unique_words) {
+			t.Errorf("doc %d unique_words = %v, want %v", i, g.unique_words, w.unique_words)
+		}
+		if !reflect.DeepEqual(g.wordtopics_indices, w.wordtopics_indices) {
+			t.Errorf("doc %d wordtopics_indices = %v, want %v", i, g.wordtopics_indices, w.wordtopics_indices)
+		}
+		if !reflect.DeepEqual(g.wordtopics, w.wordtopics) {
+			t.Errorf("doc %d wordtopics = %v, want %v", i, g.wordtopics, w.wordtopics)
+		}
+		if !reflect.DeepEqual([]int(g.topic_histogram), []int(w.topic_histogram)) {
+			t.Errorf("doc %d topic_histogram = %v, want %v", i, g.topic_histogram, w.topic_histogram)
+		}
+	}
+}
+
+func TestSaveLoadCorpusBinaryRoundTrip(t *testing.T) {
+	corpus := buildBinaryTestCorpus(t)
+	path := filepath.Join(t.TempDir(), "corpus.bin")
+
+	if err := SaveCorpusBinary(path, corpus); err != nil {
+		t.Fatalf("SaveCorpusBinary: %v", err)
+	}
+
+	loaded, err := LoadCorpusBinary(path)
+	if err != nil {
+		t.Fatalf("LoadCorpusBinary: %v", err)
+	}
+	assertCorporaEqual(t, loaded, corpus)
+}
+
+func TestSaveCorpusBinaryRejectsEmptyCorpus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.bin")
+	if err := SaveCorpusBinary(path, NewCorpus()); err == nil {
+		t.Fatal("SaveCorpusBinary(empty corpus) = nil error, want error")
+	}
+}
+
+func TestLoadCorpusBinaryRejectsBadMagic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garbage.bin")
+	if err := os.WriteFile(path, []byte("not a corpus file"), 0644); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+	if _, err := LoadCorpusBinary(path); err == nil {
+		t.Fatal("LoadCorpusBinary(garbage) = nil error, want error")
+	}
+}
@@ -0,0 +1,254 @@
+package lda
+
+import (
+	"encoding/binary"
+	"errors"
+	"index/suffixarray"
+	"os"
+	"sort"
+)
+
+// symbolWidth is the number of bytes used to encode each word's symbol id
+// in the byte stream handed to suffixarray.  Using a fixed width lets
+// PhraseSearch reject matches that land on a non-token boundary.
+const symbolWidth = 4
+
+// docBoundarySymbol is inserted between documents' token streams before
+// building the suffix array, so a multi-word PhraseSearch pattern can
+// never match across a document boundary: its bytes can't occur in any
+// real word's id (ids are allocated from 0 up), so no real pattern can
+// contain it, and any span that would straddle two documents now has
+// docBoundarySymbol's bytes sitting in the middle of it instead of being
+// contiguous real tokens.
+const docBoundarySymbol uint32 = 1<<32 - 1
+
+// DocRef points at a document that contains a word, along with how many
+// times that word occurs in it.
+type DocRef struct {
+	Doc   int
+	Count int
+}
+
+// Match reports a phrase occurrence: the document it was found in and the
+// word offset within that document's token stream.
+type Match struct {
+	Doc      int
+	Position int
+}
+
+// CorpusIndex is a read-only inverted index over the text used to build a
+// Corpus.  It lets callers look up which documents contain a word
+// (Postings, Contains) or find phrase occurrences (PhraseSearch), none of
+// which is recoverable from Corpus/Document alone since NewDocument
+// discards word order and collapses repeated words into unique_words.
+//
+// CorpusIndex interns its own symbol table rather than re-encoding
+// Document.unique_words as []uint32 against a table shared with it: doing
+// that would mean every WordIterator.Word() call (the hottest path in
+// SampleParallel's per-occurrence Gibbs resampling, run concurrently by
+// every worker on every iteration) would need to read through a mutex-
+// guarded global table to turn a symbol id back into a string, trading a
+// memory win for lock contention on the sampler's hot path. Keeping
+// Document's own string-deduplicated unique_words and building
+// CorpusIndex's table separately avoids that regression; it costs extra
+// memory proportional to vocabulary size, which is the tradeoff this type
+// makes.
+type CorpusIndex struct {
+	symbols   map[string]uint32
+	postings  map[uint32][]DocRef
+	docStart  []int // index, in the symbol-encoded stream, of each document's first token
+	docEnd    []int // index right after each document's last token (before its boundary symbol, if any)
+	data      []byte
+	sa        *suffixarray.Index
+	tokenizer Tokenizer
+}
+
+func encodeSymbolIDs(ids []uint32) []byte {
+	buf := make([]byte, len(ids)*symbolWidth)
+	for i, id := range ids {
+		binary.BigEndian.PutUint32(buf[i*symbolWidth:], id)
+	}
+	return buf
+}
+
+// NewCorpusIndex builds a CorpusIndex from the raw (untokenized) text of
+// each document, in the same order they were passed to NewDocument when
+// building the corresponding Corpus.  It tokenizes with WithTokenizer's
+// Tokenizer if opts sets one (the same option NewDocument/LoadCorpus take),
+// and otherwise defaults to WhitespaceTokenizer, so word lookups agree
+// with however the matching Corpus was actually tokenized. Stopword and
+// vocabulary options in opts are not applied: the index holds every token
+// the Tokenizer produces, including words those filters drop from the
+// Corpus's Documents.
+func NewCorpusIndex(texts []string, opts ...CorpusOption) (*CorpusIndex, error) {
+	if len(texts) == 0 {
+		return nil, errors.New("NewCorpusIndex requires at least one document")
+	}
+
+	o := newCorpusOptions(opts...)
+	idx := &CorpusIndex{
+		symbols:   make(map[string]uint32),
+		postings:  make(map[uint32][]DocRef),
+		docStart:  make([]int, 0, len(texts)),
+		docEnd:    make([]int, 0, len(texts)),
+		tokenizer: o.Tokenizer,
+	}
+
+	var tokenIDs []uint32
+	for doc, text := range texts {
+		if doc > 0 {
+			tokenIDs = append(tokenIDs, docBoundarySymbol)
+		}
+		idx.docStart = append(idx.docStart, len(tokenIDs))
+
+		words := idx.tokenizer.Tokenize(text)
+		counts := make(map[uint32]int, len(words))
+		for _, w := range words {
+			id, ok := idx.symbols[w]
+			if !ok {
+				id = uint32(len(idx.symbols))
+				idx.symbols[w] = id
+			}
+			tokenIDs = append(tokenIDs, id)
+			counts[id]++
+		}
+		for id, count := range counts {
+			idx.postings[id] = append(idx.postings[id], DocRef{Doc: doc, Count: count})
+		}
+		idx.docEnd = append(idx.docEnd, len(tokenIDs))
+	}
+
+	idx.data = encodeSymbolIDs(tokenIDs)
+	idx.sa = suffixarray.New(idx.data)
+	return idx, nil
+}
+
+// Contains reports whether word appears in any indexed document.
+func (idx *CorpusIndex) Contains(word string) bool {
+	_, ok := idx.symbols[idx.normalizeWord(word)]
+	return ok
+}
+
+// Postings returns, for each document containing word, a DocRef with its
+// occurrence count.  It returns nil if word was never indexed.
+func (idx *CorpusIndex) Postings(word string) []DocRef {
+	id, ok := idx.symbols[idx.normalizeWord(word)]
+	if !ok {
+		return nil
+	}
+	refs := idx.postings[id]
+	out := make([]DocRef, len(refs))
+	copy(out, refs)
+	return out
+}
+
+// PhraseSearch finds every occurrence of phrase (tokenized the same way as
+// a document) across the indexed corpus. It returns nil if any word in the
+// phrase was never indexed, since no document could then contain it.
+func (idx *CorpusIndex) PhraseSearch(phrase string) []Match {
+	words := idx.tokenizer.Tokenize(phrase)
+	if len(words) == 0 {
+		return nil
+	}
+
+	ids := make([]uint32, len(words))
+	for i, w := range words {
+		id, ok := idx.symbols[w]
+		if !ok || id == docBoundarySymbol {
+			return nil
+		}
+		ids[i] = id
+	}
+
+	pattern := encodeSymbolIDs(ids)
+	offsets := idx.sa.Lookup(pattern, -1)
+
+	matches := make([]Match, 0, len(offsets))
+	for _, off := range offsets {
+		if off%symbolWidth != 0 {
+			continue // misaligned match spanning two symbols' encodings
+		}
+		token := off / symbolWidth
+		doc, ok := idx.docForToken(token)
+		if !ok {
+			continue // shouldn't happen: a real pattern can't match a boundary symbol
+		}
+		matches = append(matches, Match{Doc: doc, Position: token - idx.docStart[doc]})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Doc != matches[j].Doc {
+			return matches[i].Doc < matches[j].Doc
+		}
+		return matches[i].Position < matches[j].Position
+	})
+	return matches
+}
+
+// docForToken returns the index of the document whose real (non-boundary)
+// token range contains token, and false if token instead falls on a
+// docBoundarySymbol inserted between documents.
+func (idx *CorpusIndex) docForToken(token int) (doc int, ok bool) {
+	doc = sort.Search(len(idx.docEnd), func(i int) bool {
+		return idx.docEnd[i] > token
+	})
+	if doc >= len(idx.docEnd) || token < idx.docStart[doc] {
+		return 0, false
+	}
+	return doc, true
+}
+
+// LoadCorpusIndexed behaves like LoadCorpus but also builds a CorpusIndex
+// over the lines that became Documents, so the returned Corpus and
+// CorpusIndex stay aligned: corpus element i corresponds to document i in
+// the index.
+func LoadCorpusIndexed(filename string, num_topics int, opts ...CorpusOption) (*Corpus, *CorpusIndex, error) {
+	file, err := os.OpenFile(filename, 0, 0)
+	if err != nil {
+		return nil, nil, errors.New("Cannot open file: " + filename)
+	}
+	defer file.Close()
+
+	corpus := NewCorpus()
+	var texts []string
+
+	o := newCorpusOptions(opts...)
+	done := make(chan struct{})
+	defer close(done)
+
+	lines, lineErrs := streamLines(file, o, done)
+	for line := range lines {
+		doc, err := buildDocument(line, num_topics, o)
+		if err != nil {
+			if o.OnError == nil {
+				return nil, nil, err
+			}
+			if err = o.OnError(line, err); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		*corpus = append(*corpus, doc)
+		texts = append(texts, line)
+	}
+	if err := <-lineErrs; err != nil {
+		return nil, nil, err
+	}
+
+	if len(texts) == 0 {
+		return corpus, nil, nil
+	}
+	index, err := NewCorpusIndex(texts, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return corpus, index, nil
+}
+
+func (idx *CorpusIndex) normalizeWord(word string) string {
+	words := idx.tokenizer.Tokenize(word)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
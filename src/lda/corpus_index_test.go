@@ -0,0 +1,101 @@
+package lda
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCorpusIndexContainsAndPostings(t *testing.T) {
+	idx, err := NewCorpusIndex([]string{
+		"the quick brown fox",
+		"the lazy dog sleeps",
+	})
+	if err != nil {
+		t.Fatalf("NewCorpusIndex: %v", err)
+	}
+
+	if !idx.Contains("fox") {
+		t.Error(`Contains("fox") = false, want true`)
+	}
+	if idx.Contains("elephant") {
+		t.Error(`Contains("elephant") = true, want false`)
+	}
+
+	refs := idx.Postings("the")
+	if len(refs) != 2 {
+		t.Fatalf("Postings(\"the\") returned %d refs, want 2", len(refs))
+	}
+	for _, ref := range refs {
+		if ref.Count != 1 {
+			t.Errorf("doc %d: count = %d, want 1", ref.Doc, ref.Count)
+		}
+	}
+
+	if got := idx.Postings("elephant"); got != nil {
+		t.Errorf("Postings(\"elephant\") = %v, want nil", got)
+	}
+}
+
+func TestCorpusIndexPhraseSearch(t *testing.T) {
+	idx, err := NewCorpusIndex([]string{
+		"the quick brown fox jumps",
+		"jumps over the lazy dog",
+	})
+	if err != nil {
+		t.Fatalf("NewCorpusIndex: %v", err)
+	}
+
+	matches := idx.PhraseSearch("quick brown fox")
+	if len(matches) != 1 {
+		t.Fatalf("PhraseSearch(\"quick brown fox\") = %v, want 1 match", matches)
+	}
+	if matches[0].Doc != 0 || matches[0].Position != 1 {
+		t.Errorf("match = %+v, want {Doc:0 Position:1}", matches[0])
+	}
+
+	// "fox" and "over" are never adjacent in any document.
+	if got := idx.PhraseSearch("fox over"); len(got) != 0 {
+		t.Errorf("PhraseSearch(\"fox over\") = %v, want no matches (words never adjacent)", got)
+	}
+
+	// Document 0 ends with "jumps" and document 1 starts with it, so
+	// "jumps jumps" would match across the join if the token streams were
+	// simply concatenated; the docBoundarySymbol inserted between documents
+	// must prevent this from being reported as a match.
+	if got := idx.PhraseSearch("jumps jumps"); len(got) != 0 {
+		t.Errorf("PhraseSearch(\"jumps jumps\") = %v, want no matches (spans a document boundary)", got)
+	}
+
+	if got := idx.PhraseSearch("fox jumps"); len(got) != 1 || got[0].Doc != 0 {
+		t.Errorf("PhraseSearch(\"fox jumps\") = %v, want exactly one match in doc 0", got)
+	}
+
+	if got := idx.PhraseSearch("nonexistent phrase"); got != nil {
+		t.Errorf("PhraseSearch(nonexistent) = %v, want nil", got)
+	}
+}
+
+func TestLoadCorpusIndexedAbortDoesNotLeakGoroutine(t *testing.T) {
+	before := countGoroutines(t)
+
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	contents := "x\nthe quick brown fox jumps over the lazy dog\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	if _, _, err := LoadCorpusIndexed(path, 3); err == nil {
+		t.Fatal("LoadCorpusIndexed with a too-short first line = nil error, want error")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if countGoroutines(t) <= before {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("goroutine count stayed above baseline (%d) after LoadCorpusIndexed aborted early", before)
+}
@@ -0,0 +1,42 @@
+package lda
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCorpusIndexHonorsTokenizer(t *testing.T) {
+	// Commas carry no meaning to WhitespaceTokenizer's whitespace split, so
+	// without WithTokenizer these would stay glued into one non-matching
+	// token; UnicodeWordTokenizer splits on the punctuation instead.
+	idx, err := NewCorpusIndex([]string{"猫,犬,鳥", "馬,牛,羊"}, WithTokenizer(UnicodeWordTokenizer{}))
+	if err != nil {
+		t.Fatalf("NewCorpusIndex: %v", err)
+	}
+	if !idx.Contains("猫") {
+		t.Error(`Contains("猫") = false, want true (UnicodeWordTokenizer should split on punctuation)`)
+	}
+	if idx.Contains("パンダ") {
+		t.Error(`Contains("パンダ") = true, want false`)
+	}
+}
+
+func TestLoadCorpusIndexedHonorsTokenizer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	contents := "猫,窓,外\n犬,庭,遊ぶ\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	_, index, err := LoadCorpusIndexed(path, 3, WithTokenizer(UnicodeWordTokenizer{}), WithMinDocWords(1))
+	if err != nil {
+		t.Fatalf("LoadCorpusIndexed: %v", err)
+	}
+	if index == nil {
+		t.Fatal("LoadCorpusIndexed returned a nil index")
+	}
+	if !index.Contains("猫") {
+		t.Error(`index.Contains("猫") = false, want true: index should use the same Tokenizer as the Documents it indexes`)
+	}
+}
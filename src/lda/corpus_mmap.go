@@ -0,0 +1,72 @@
+//go:build unix
+
+package lda
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"syscall"
+)
+
+// LoadCorpusMMap reads a Corpus from path, which must be in the binary
+// corpus format written by SaveCorpusBinary, by memory-mapping the file
+// with syscall.Mmap and letting the OS page it in as the decoder walks
+// it. Every call still decodes every symbol and document in the file, so
+// it does the same decoding work as LoadCorpusBinary; only the source of
+// the bytes differs.
+//
+// The mapping is unmapped before LoadCorpusMMap returns, so unlike a true
+// zero-copy reader the decoded symbol strings and document slices are
+// still copied out of it (via the same decoding path LoadCorpusBinary
+// uses) rather than aliasing the mapped pages after the fact. Returning
+// Document values that alias the mapping would mean keeping it mapped for
+// as long as the returned Corpus is reachable, which needs an explicit
+// Close()-style lifetime the rest of this package doesn't have anywhere
+// else; without one, any access after this function unmaps would read
+// freed pages. Paging the binary file in lazily, rather than that, is
+// the safe subset of the win a true zero-copy reader would give.
+func LoadCorpusMMap(path string) (*Corpus, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New("Cannot open file: " + path)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, errors.New("Cannot stat file: " + path)
+	}
+	if info.Size() == 0 {
+		return nil, errors.New("Cannot mmap empty file: " + path)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, errors.New("Cannot mmap file: " + path + ": " + err.Error())
+	}
+	defer syscall.Munmap(data)
+
+	r := bufio.NewReader(bytes.NewReader(data))
+	numTopics, symbols, err := readCorpusBinaryHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	numDocs, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errors.New("Error reading corpus: " + err.Error())
+	}
+
+	corpus := NewCorpus()
+	for i := uint64(0); i < numDocs; i++ {
+		doc, err := readCorpusBinaryDocument(r, numTopics, symbols)
+		if err != nil {
+			return nil, err
+		}
+		*corpus = append(*corpus, doc)
+	}
+	return corpus, nil
+}
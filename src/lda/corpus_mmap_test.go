@@ -0,0 +1,34 @@
+//go:build unix
+
+package lda
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCorpusMMapMatchesLoadCorpusBinary(t *testing.T) {
+	corpus := buildBinaryTestCorpus(t)
+	path := filepath.Join(t.TempDir(), "corpus.bin")
+
+	if err := SaveCorpusBinary(path, corpus); err != nil {
+		t.Fatalf("SaveCorpusBinary: %v", err)
+	}
+
+	loaded, err := LoadCorpusMMap(path)
+	if err != nil {
+		t.Fatalf("LoadCorpusMMap: %v", err)
+	}
+	assertCorporaEqual(t, loaded, corpus)
+}
+
+func TestLoadCorpusMMapRejectsEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.bin")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+	if _, err := LoadCorpusMMap(path); err == nil {
+		t.Fatal("LoadCorpusMMap(empty file) = nil error, want error")
+	}
+}
@@ -0,0 +1,224 @@
+package lda
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// defaultMaxLineBytes bounds how long a single document line may be
+// before StreamCorpus/LoadCorpus refuse to buffer it.  This replaces the
+// implicit limit that used to come from bufio.Reader's internal buffer.
+const defaultMaxLineBytes = 1024 * 1024
+
+// CorpusOptions configures how LoadCorpus and StreamCorpus turn raw text
+// into Documents.  Use the With* functions to build a CorpusOption list
+// rather than constructing CorpusOptions directly.
+type CorpusOptions struct {
+	MaxLineBytes int
+	MinDocWords  int
+	Stopwords    map[string]struct{}
+	Vocabulary   map[string]struct{}
+	OnError      func(line string, err error) error
+	SplitFunc    bufio.SplitFunc
+	Tokenizer    Tokenizer
+}
+
+// CorpusOption mutates a CorpusOptions in place.
+type CorpusOption func(*CorpusOptions)
+
+// WithMaxLineBytes overrides the maximum number of bytes a single document
+// line may occupy.  Lines longer than this are reported as an error.
+func WithMaxLineBytes(n int) CorpusOption {
+	return func(o *CorpusOptions) { o.MaxLineBytes = n }
+}
+
+// WithMinDocWords overrides the minimum number of (post-filtering) words a
+// line must have to become a Document.  A shorter line fails to parse and
+// is handled like any other parse failure (see WithOnError). Values below
+// 2 do not admit one-word lines, since a Document needs at least 2 words.
+func WithMinDocWords(n int) CorpusOption {
+	return func(o *CorpusOptions) { o.MinDocWords = n }
+}
+
+// WithStopwords registers a set of words to drop before a Document is
+// built.  Matching is case-insensitive, matching NewDocument's
+// lower-casing: stopwords is lower-cased into a fresh map here, so
+// callers may pass mixed-case keys.
+func WithStopwords(stopwords map[string]struct{}) CorpusOption {
+	lowered := make(map[string]struct{}, len(stopwords))
+	for w := range stopwords {
+		lowered[strings.ToLower(w)] = struct{}{}
+	}
+	return func(o *CorpusOptions) { o.Stopwords = lowered }
+}
+
+// WithVocabulary restricts Documents to only the given allow-listed words.
+// A nil or empty vocabulary means no restriction is applied.
+func WithVocabulary(vocabulary map[string]struct{}) CorpusOption {
+	return func(o *CorpusOptions) { o.Vocabulary = vocabulary }
+}
+
+// WithOnError installs a callback invoked whenever a line fails to become
+// a Document.  Returning nil skips the line; returning a non-nil error
+// aborts the stream with that error.  If unset, parse failures abort the
+// stream with the original error, replacing the previous panic-on-failure
+// behavior of LoadCorpus with a plain error return.
+func WithOnError(f func(line string, err error) error) CorpusOption {
+	return func(o *CorpusOptions) { o.OnError = f }
+}
+
+// WithSplitFunc overrides how the input is split into candidate document
+// lines.  It defaults to bufio.ScanLines.
+func WithSplitFunc(split bufio.SplitFunc) CorpusOption {
+	return func(o *CorpusOptions) { o.SplitFunc = split }
+}
+
+// WithTokenizer overrides how a line's text is split into words. It
+// defaults to WhitespaceTokenizer, NewDocument's original behavior.
+func WithTokenizer(t Tokenizer) CorpusOption {
+	return func(o *CorpusOptions) { o.Tokenizer = t }
+}
+
+func newCorpusOptions(opts ...CorpusOption) *CorpusOptions {
+	o := &CorpusOptions{
+		MaxLineBytes: defaultMaxLineBytes,
+		MinDocWords:  2,
+		SplitFunc:    bufio.ScanLines,
+		Tokenizer:    WhitespaceTokenizer{},
+	}
+	for _, apply := range opts {
+		apply(o)
+	}
+	return o
+}
+
+// filterWords drops stopwords and, when a vocabulary is set, anything not
+// in that vocabulary.  It is applied before words are handed to
+// NewDocument, per CorpusOptions.Stopwords / CorpusOptions.Vocabulary.
+func filterWords(words []string, o *CorpusOptions) []string {
+	if len(o.Stopwords) == 0 && len(o.Vocabulary) == 0 {
+		return words
+	}
+	filtered := words[:0:0]
+	for _, w := range words {
+		if len(o.Stopwords) > 0 {
+			if _, skip := o.Stopwords[w]; skip {
+				continue
+			}
+		}
+		if len(o.Vocabulary) > 0 {
+			if _, ok := o.Vocabulary[w]; !ok {
+				continue
+			}
+		}
+		filtered = append(filtered, w)
+	}
+	return filtered
+}
+
+// streamLines splits reader into candidate document lines per o.SplitFunc
+// and o.MaxLineBytes.  It underlies both StreamCorpus and
+// LoadCorpusIndexed, which need the raw lines before (and in the latter
+// case, in addition to) turning them into Documents.  Closing done tells
+// the producer goroutine to stop scanning and exit even if the caller
+// stops ranging over lines before reader is exhausted; callers that range
+// over lines to completion don't need to close it themselves, but callers
+// that may return early (e.g. on error) must close it to avoid leaking
+// the goroutine.
+func streamLines(reader io.Reader, o *CorpusOptions, done <-chan struct{}) (<-chan string, <-chan error) {
+	lines := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(lines)
+		defer close(errs)
+
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 0, 64*1024), o.MaxLineBytes)
+		scanner.Split(o.SplitFunc)
+
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-done:
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case errs <- errors.New("Error reading corpus: " + err.Error()):
+			case <-done:
+			}
+		}
+	}()
+
+	return lines, errs
+}
+
+// StreamCorpus reads documents from reader one line at a time, applying
+// opts, and returns them on a channel rather than materializing a full
+// Corpus slice in memory.  Both channels are closed once reader is
+// exhausted or an unrecoverable error occurs; the error channel receives
+// at most one value.
+func StreamCorpus(reader io.Reader, num_topics int, opts ...CorpusOption) (<-chan *Document, <-chan error) {
+	docs := make(chan *Document)
+	errs := make(chan error, 1)
+
+	o := newCorpusOptions(opts...)
+
+	go func() {
+		defer close(docs)
+		defer close(errs)
+
+		done := make(chan struct{})
+		defer close(done)
+
+		lines, lineErrs := streamLines(reader, o, done)
+		for line := range lines {
+			doc, err := buildDocument(line, num_topics, o)
+			if err != nil {
+				if o.OnError == nil {
+					errs <- err
+					return
+				}
+				if err = o.OnError(line, err); err != nil {
+					errs <- err
+					return
+				}
+				continue
+			}
+			docs <- doc
+		}
+		if err := <-lineErrs; err != nil {
+			errs <- err
+		}
+	}()
+
+	return docs, errs
+}
+
+// LoadCorpus reads filename line by line and builds a Corpus, applying
+// opts if given.  Unlike the original implementation, a line that fails
+// to parse into a Document returns an error instead of panicking; pass
+// WithOnError to skip such lines instead of aborting, or to observe them
+// before deciding.
+func LoadCorpus(filename string, num_topics int, opts ...CorpusOption) (corpus *Corpus, err error) {
+	file, err := os.OpenFile(filename, 0, 0)
+	if err != nil {
+		return nil, errors.New("Cannot open file: " + filename)
+	}
+	defer file.Close()
+
+	corpus = NewCorpus()
+	docs, errs := StreamCorpus(file, num_topics, opts...)
+	for doc := range docs {
+		*corpus = append(*corpus, doc)
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return corpus, nil
+}
@@ -0,0 +1,164 @@
+package lda
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func countGoroutines(t *testing.T) int {
+	t.Helper()
+	runtime.GC()
+	return runtime.NumGoroutine()
+}
+
+// TestStreamCorpusAbortDoesNotLeakGoroutine guards against StreamCorpus's
+// producer goroutine (started inside streamLines) blocking forever on
+// `lines <- scanner.Text()` after the consumer goroutine returns early on
+// a parse error, since nothing would otherwise drain the lines channel
+// again.
+func TestStreamCorpusAbortDoesNotLeakGoroutine(t *testing.T) {
+	before := countGoroutines(t)
+
+	lines := make([]string, 0, 10)
+	lines = append(lines, "x") // fails MinDocWords, triggers abort on line 1
+	for i := 0; i < 9; i++ {
+		lines = append(lines, "the quick brown fox jumps over the lazy dog")
+	}
+	reader := strings.NewReader(strings.Join(lines, "\n"))
+
+	docs, errs := StreamCorpus(reader, 3)
+	for range docs {
+	}
+	if err := <-errs; err == nil {
+		t.Fatal("StreamCorpus with a too-short first line = nil error, want error")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if countGoroutines(t) <= before {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("goroutine count stayed above baseline (%d) after StreamCorpus aborted early", before)
+}
+
+// TestStreamCorpusFiltersStopwordsAndVocabulary exercises filterWords via
+// StreamCorpus: stopwords are dropped case-insensitively (matching
+// NewDocument's lower-casing) and, when a vocabulary is set, only
+// allow-listed words survive.
+func TestStreamCorpusFiltersStopwordsAndVocabulary(t *testing.T) {
+	reader := strings.NewReader("The Quick brown fox jumps over the lazy dog\n")
+	docs, errs := StreamCorpus(reader, 3,
+		WithStopwords(map[string]struct{}{"the": {}, "over": {}}),
+		WithVocabulary(map[string]struct{}{
+			"quick": {}, "brown": {}, "fox": {}, "jumps": {}, "lazy": {}, "dog": {},
+		}),
+	)
+
+	var doc *Document
+	for d := range docs {
+		doc = d
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("StreamCorpus: %v", err)
+	}
+	if doc == nil {
+		t.Fatal("StreamCorpus produced no document")
+	}
+	if doc.Length() != 6 {
+		t.Fatalf("document has %d words, want 6 (stopwords dropped)", doc.Length())
+	}
+	for _, w := range doc.unique_words {
+		if w == "the" || w == "over" {
+			t.Errorf("stopword %q survived filtering", w)
+		}
+	}
+}
+
+// TestStreamCorpusOnErrorCanSkip exercises the documented skip path:
+// returning nil from OnError drops the failing line and continues.
+func TestStreamCorpusOnErrorCanSkip(t *testing.T) {
+	reader := strings.NewReader("x\nthe quick brown fox jumps over the lazy dog\n")
+	var skipped []string
+	docs, errs := StreamCorpus(reader, 3, WithOnError(func(line string, err error) error {
+		skipped = append(skipped, line)
+		return nil
+	}))
+
+	var got []*Document
+	for d := range docs {
+		got = append(got, d)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("StreamCorpus: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d documents, want 1", len(got))
+	}
+	if len(skipped) != 1 || skipped[0] != "x" {
+		t.Fatalf("OnError saw %v, want [\"x\"]", skipped)
+	}
+}
+
+// TestStreamCorpusOnErrorCanAbort exercises the documented abort path:
+// returning a non-nil error from OnError stops the stream with that error.
+func TestStreamCorpusOnErrorCanAbort(t *testing.T) {
+	reader := strings.NewReader("x\nthe quick brown fox jumps over the lazy dog\n")
+	sentinel := errors.New("abort")
+	docs, errs := StreamCorpus(reader, 3, WithOnError(func(line string, err error) error {
+		return sentinel
+	}))
+
+	for range docs {
+	}
+	if err := <-errs; err != sentinel {
+		t.Fatalf("StreamCorpus error = %v, want %v", err, sentinel)
+	}
+}
+
+// TestWithStopwordsMatchesCaseInsensitively guards against WithStopwords
+// doing a case-sensitive lookup against already-lower-cased tokenized
+// words: a caller passing mixed-case stopword keys (as the doc comment
+// says is fine) must still have them dropped.
+func TestWithStopwordsMatchesCaseInsensitively(t *testing.T) {
+	reader := strings.NewReader("The the Quick brown fox\n")
+	docs, errs := StreamCorpus(reader, 3, WithStopwords(map[string]struct{}{"The": {}}))
+
+	var doc *Document
+	for d := range docs {
+		doc = d
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("StreamCorpus: %v", err)
+	}
+	if doc == nil {
+		t.Fatal("StreamCorpus produced no document")
+	}
+	for _, w := range doc.unique_words {
+		if w == "the" {
+			t.Error(`stopword "The" (mixed case) did not drop lower-cased "the" from the document`)
+		}
+	}
+}
+
+// TestLoadCorpusAbortsByDefaultOnParseError exercises LoadCorpus's
+// documented behavior that, without WithOnError, a line that fails to
+// parse aborts the whole load with an error rather than being silently
+// skipped.
+func TestLoadCorpusAbortsByDefaultOnParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	contents := "x\nthe quick brown fox jumps over the lazy dog\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	if _, err := LoadCorpus(path, 3); err == nil {
+		t.Fatal("LoadCorpus with a too-short first line = nil error, want error")
+	}
+}
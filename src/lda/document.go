@@ -1,18 +1,18 @@
 package lda
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"io"
-	"os"
 	"regexp"
 	"sort"
-	"strings"
 )
 
 // const kMaxCorpusFileLineLength = 1024 * 1024
 
+// Histogram counts how many words are assigned to each topic, indexed by
+// topic id.
+type Histogram []int
+
 // Document contains some unique words, each has one or more
 // occurrences in this document.  Each occurrence has a topic
 // assignment, where topic is an integer from 0 to K-1.
@@ -65,12 +65,17 @@ func (iter *WordIterator) Next() {
 		panic("Must not call Next() when Done() is true.")
 	}
 	iter.word_topic_index++
-	if iter.word_topic_index >= len(iter.doc.wordtopics) ||
-		iter.word_topic_index >=
-			iter.doc.wordtopics_indices[iter.unique_word_index+1] {
+	if iter.word_topic_index >= len(iter.doc.wordtopics) {
+		iter.unique_word_index = len(iter.doc.unique_words)
+		return
+	}
+	// wordtopics_indices[j] == j exactly when occurrence j starts a new
+	// unique word's run (see newDocumentFromWords): repeats copy the
+	// run's start index forward, so a changed value can only mean the
+	// run just started at j itself.
+	if iter.doc.wordtopics_indices[iter.word_topic_index] == iter.word_topic_index {
 		iter.unique_word_index++
 	}
-
 }
 
 func (iter WordIterator) Topic() int {
@@ -109,17 +114,47 @@ func RemoveTailingSymbols(word string) string {
 	return SymbolsRegexp.ReplaceAllString(word, "")
 }
 
-// Parse a text string, words seprated by whitespaces, and create a
-// Document instance.  In order to initialize topic_histogram, this
-// function requires the number_of_topics.
-func NewDocument(text string, num_topics int) (doc *Document, err error) {
+// tokenizeForDocument applies the default whitespace/symbol tokenization
+// (WhitespaceTokenizer) that NewDocument uses when no Tokenizer is set via
+// WithTokenizer.
+func tokenizeForDocument(text string) []string {
+	return WhitespaceTokenizer{}.Tokenize(text)
+}
+
+// Parse a text string and create a Document instance.  In order to
+// initialize topic_histogram, this function requires the
+// number_of_topics. By default words are split with WhitespaceTokenizer;
+// pass WithTokenizer to use a different Tokenizer, and WithStopwords /
+// WithVocabulary to filter the resulting words before the Document is
+// built.
+func NewDocument(text string, num_topics int, opts ...CorpusOption) (doc *Document, err error) {
 	if num_topics <= 1 {
 		return nil, errors.New("num_topics must be >= 2")
 	}
+	return buildDocument(text, num_topics, newCorpusOptions(opts...))
+}
+
+// buildDocument tokenizes text with o.Tokenizer, applies o's stopword and
+// vocabulary filters, and builds a Document if at least o.MinDocWords
+// words remain. It is the shared core behind NewDocument, LoadCorpus and
+// StreamCorpus.
+func buildDocument(text string, num_topics int, o *CorpusOptions) (doc *Document, err error) {
+	words := filterWords(o.Tokenizer.Tokenize(text), o)
+	if len(words) < o.MinDocWords {
+		return nil, errors.New("Document has fewer than MinDocWords words:" + text)
+	}
+	return newDocumentFromWords(words, num_topics)
+}
 
-	words := strings.Fields(strings.ToLower(RemoveTailingSymbols(text)))
+// newDocumentFromWords builds a Document from an already-tokenized and
+// already-filtered word list, sharing the histogram/index construction
+// logic between NewDocument and the CorpusOptions-aware loaders.
+func newDocumentFromWords(words []string, num_topics int) (doc *Document, err error) {
+	if num_topics <= 1 {
+		return nil, errors.New("num_topics must be >= 2")
+	}
 	if len(words) <= 1 {
-		return nil, errors.New("Document less than 2 words:" + text)
+		return nil, errors.New("Document less than 2 words")
 	}
 	sort.Strings(words)
 
@@ -162,38 +197,3 @@ func (d Document) Length() int {
 func NewCorpus() *Corpus {
 	return &Corpus{}
 }
-
-func LoadCorpus(filename string, num_topics int) (corpus *Corpus, err error) {
-	file, err := os.OpenFile(filename, 0, 0)
-	if err != nil {
-		return nil, errors.New("Cannot open file: " + filename)
-	}
-	defer file.Close()
-
-	corpus = NewCorpus()
-	reader := bufio.NewReader(file)
-	l, is_prefix, err := reader.ReadLine()
-	for err == nil {
-		line := string(l)
-
-		if is_prefix {
-			return nil, errors.New("Encountered a long line:" + line)
-		}
-
-		if len(l) > 15 { // skip short lines
-			doc, err := NewDocument(line, num_topics)
-			if err == nil {
-				*corpus = append(*corpus, doc)
-			} else {
-				panic("Cannot create document from: " + line + " due to " + err.Error())
-			}
-		}
-
-		l, _, err = reader.ReadLine()
-	}
-
-	if err != io.EOF {
-		return nil, errors.New("Error reading: " + filename + err.Error())
-	}
-	return corpus, nil
-}
@@ -0,0 +1,47 @@
+package lda
+
+import "testing"
+
+// TestWordIteratorVisitsEveryOccurrence guards against a bug where Next()
+// advanced unique_word_index using wordtopics_indices as if it were indexed
+// per unique word, when it's actually indexed per occurrence; on a document
+// with a repeated word that made the iterator stop early and mislabel the
+// occurrences past the first repeat.
+func TestWordIteratorVisitsEveryOccurrence(t *testing.T) {
+	doc, err := NewDocument("the quick brown fox jumps over the lazy dog", 3)
+	if err != nil {
+		t.Fatalf("NewDocument: %v", err)
+	}
+
+	iter, err := NewWordIterator(doc)
+	if err != nil {
+		t.Fatalf("NewWordIterator: %v", err)
+	}
+
+	wantCounts := map[string]int{
+		"the": 2, "quick": 1, "brown": 1, "fox": 1,
+		"jumps": 1, "over": 1, "lazy": 1, "dog": 1,
+	}
+
+	gotCounts := make(map[string]int)
+	visited := 0
+	for !iter.Done() {
+		gotCounts[iter.Word()]++
+		visited++
+		iter.Next()
+	}
+
+	if visited != doc.Length() {
+		t.Fatalf("iterator visited %d slots, want %d (doc.Length())", visited, doc.Length())
+	}
+	for word, want := range wantCounts {
+		if gotCounts[word] != want {
+			t.Errorf("word %q visited %d times, want %d", word, gotCounts[word], want)
+		}
+	}
+	for word, got := range gotCounts {
+		if _, ok := wantCounts[word]; !ok {
+			t.Errorf("unexpected word %q visited %d times", word, got)
+		}
+	}
+}
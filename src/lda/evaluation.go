@@ -0,0 +1,171 @@
+package lda
+
+import (
+	"container/heap"
+	"math"
+)
+
+// WordProb pairs a word with its probability under some topic.
+type WordProb struct {
+	Word string
+	Prob float64
+}
+
+// TopicProb pairs a topic with its probability within some document.
+type TopicProb struct {
+	Topic int
+	Prob  float64
+}
+
+// wordProbHeap is a min-heap on Prob, so the smallest of the k entries
+// kept so far is always at the root and can be evicted in O(log k) when a
+// more probable word is found.
+type wordProbHeap []WordProb
+
+func (h wordProbHeap) Len() int            { return len(h) }
+func (h wordProbHeap) Less(i, j int) bool  { return h[i].Prob < h[j].Prob }
+func (h wordProbHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *wordProbHeap) Push(x interface{}) { *h = append(*h, x.(WordProb)) }
+func (h *wordProbHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// TopKWords returns the k most probable words for topic under model,
+// sorted most-to-least probable. It uses a size-k min-heap rather than
+// sorting the whole vocabulary, so it costs O(V log k) instead of
+// O(V log V) for a large model.
+func TopKWords(model *Model, topic int, k int) []WordProb {
+	if k <= 0 {
+		return nil
+	}
+	V := float64(len(model.vocabulary))
+	denom := float64(model.topicCounts[topic]) + V*model.Beta
+
+	h := &wordProbHeap{}
+	heap.Init(h)
+	for wordID, word := range model.vocabulary {
+		prob := (float64(model.wordTopics[wordID][topic]) + model.Beta) / denom
+		if h.Len() < k {
+			heap.Push(h, WordProb{Word: word, Prob: prob})
+		} else if h.Len() > 0 && prob > (*h)[0].Prob {
+			heap.Pop(h)
+			heap.Push(h, WordProb{Word: word, Prob: prob})
+		}
+	}
+
+	out := make([]WordProb, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(h).(WordProb)
+	}
+	return out
+}
+
+// topicProbHeap is a min-heap on Prob, mirroring wordProbHeap but for
+// TopKTopics.
+type topicProbHeap []TopicProb
+
+func (h topicProbHeap) Len() int            { return len(h) }
+func (h topicProbHeap) Less(i, j int) bool  { return h[i].Prob < h[j].Prob }
+func (h topicProbHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *topicProbHeap) Push(x interface{}) { *h = append(*h, x.(TopicProb)) }
+func (h *topicProbHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// TopKTopics returns doc's k most probable topics, sorted most-to-least
+// probable, estimated directly from its topic_histogram (no Dirichlet
+// smoothing, since TopKTopics has no Model/Alpha to smooth with).
+func TopKTopics(doc *Document, k int) []TopicProb {
+	if k <= 0 {
+		return nil
+	}
+	length := float64(doc.Length())
+
+	h := &topicProbHeap{}
+	heap.Init(h)
+	for topic, count := range doc.topic_histogram {
+		prob := float64(count) / length
+		if h.Len() < k {
+			heap.Push(h, TopicProb{Topic: topic, Prob: prob})
+		} else if h.Len() > 0 && prob > (*h)[0].Prob {
+			heap.Pop(h)
+			heap.Push(h, TopicProb{Topic: topic, Prob: prob})
+		}
+	}
+
+	out := make([]TopicProb, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(h).(TopicProb)
+	}
+	return out
+}
+
+// Perplexity computes held-out perplexity of corpus under model:
+// exp(-logLikelihood / totalWords). Lower is better.
+func Perplexity(corpus *Corpus, model *Model) float64 {
+	var totalWords int
+	for _, doc := range *corpus {
+		totalWords += doc.Length()
+	}
+	if totalWords == 0 {
+		return math.Inf(1)
+	}
+	return math.Exp(-model.LogLikelihood(corpus) / float64(totalWords))
+}
+
+// PerplexityStream computes perplexity the same way as Perplexity, but
+// consumes docs from a channel (e.g. one returned by StreamCorpus) one at
+// a time instead of requiring the whole held-out set materialized as a
+// Corpus.
+func PerplexityStream(docs <-chan *Document, model *Model) float64 {
+	var logLikelihood float64
+	var totalWords int
+
+	for doc := range docs {
+		logLikelihood += documentLogLikelihood(doc, model)
+		totalWords += doc.Length()
+	}
+	if totalWords == 0 {
+		return math.Inf(1)
+	}
+	return math.Exp(-logLikelihood / float64(totalWords))
+}
+
+// documentLogLikelihood computes one document's contribution to
+// Model.LogLikelihood, factored out so Perplexity/PerplexityStream can
+// share it regardless of whether the corpus is a slice or a channel.
+func documentLogLikelihood(doc *Document, model *Model) float64 {
+	V := float64(len(model.vocabulary))
+	docLen := float64(doc.Length()) + float64(model.NumTopics)*model.Alpha
+
+	iter, err := NewWordIterator(doc)
+	if err != nil {
+		return 0
+	}
+
+	var ll float64
+	for !iter.Done() {
+		wordID, ok := model.wordIndex[iter.Word()]
+		if !ok {
+			iter.Next()
+			continue
+		}
+		topic := iter.Topic()
+		wordProb := (float64(model.wordTopics[wordID][topic]) + model.Beta) /
+			(float64(model.topicCounts[topic]) + V*model.Beta)
+		docProb := (float64(doc.topic_histogram[topic]) + model.Alpha) / docLen
+		if p := wordProb * docProb; p > 0 {
+			ll += math.Log(p)
+		}
+		iter.Next()
+	}
+	return ll
+}
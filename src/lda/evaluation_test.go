@@ -0,0 +1,104 @@
+package lda
+
+import (
+	"math"
+	"testing"
+)
+
+func buildEvalModel(t *testing.T) (*Corpus, *Model) {
+	t.Helper()
+	corpus := buildTestCorpus(t, []string{
+		"the quick brown fox jumps over the lazy dog",
+		"the dog barks at the cat in the yard",
+		"quick foxes and lazy dogs rarely meet in the wild",
+	}, 3)
+	model, err := NewModelFromCorpus(corpus, 3)
+	if err != nil {
+		t.Fatalf("NewModelFromCorpus: %v", err)
+	}
+	return corpus, model
+}
+
+func TestTopKWords(t *testing.T) {
+	_, model := buildEvalModel(t)
+
+	top := TopKWords(model, 0, 3)
+	if len(top) != 3 {
+		t.Fatalf("TopKWords returned %d entries, want 3", len(top))
+	}
+	for i := 1; i < len(top); i++ {
+		if top[i-1].Prob < top[i].Prob {
+			t.Errorf("TopKWords not sorted descending: %v", top)
+		}
+	}
+
+	if got := TopKWords(model, 0, 0); got != nil {
+		t.Errorf("TopKWords(k=0) = %v, want nil", got)
+	}
+
+	full := TopKWords(model, 0, len(model.Vocabulary())+10)
+	if len(full) != len(model.Vocabulary()) {
+		t.Errorf("TopKWords(k > vocab size) returned %d entries, want %d", len(full), len(model.Vocabulary()))
+	}
+}
+
+func TestTopKTopics(t *testing.T) {
+	corpus, _ := buildEvalModel(t)
+	doc := (*corpus)[0]
+
+	top := TopKTopics(doc, 2)
+	if len(top) != 2 {
+		t.Fatalf("TopKTopics returned %d entries, want 2", len(top))
+	}
+	for i := 1; i < len(top); i++ {
+		if top[i-1].Prob < top[i].Prob {
+			t.Errorf("TopKTopics not sorted descending: %v", top)
+		}
+	}
+
+	var sum int
+	for _, count := range doc.topic_histogram {
+		sum += count
+	}
+	var probSum float64
+	for _, tp := range TopKTopics(doc, len(doc.topic_histogram)) {
+		probSum += tp.Prob
+	}
+	if math.Abs(probSum-1.0) > 1e-9 {
+		t.Errorf("TopKTopics probabilities sum to %v, want 1.0", probSum)
+	}
+
+	if got := TopKTopics(doc, 0); got != nil {
+		t.Errorf("TopKTopics(k=0) = %v, want nil", got)
+	}
+}
+
+func TestPerplexity(t *testing.T) {
+	corpus, model := buildEvalModel(t)
+
+	p := Perplexity(corpus, model)
+	if math.IsNaN(p) || math.IsInf(p, 0) || p <= 0 {
+		t.Errorf("Perplexity = %v, want a finite positive number", p)
+	}
+
+	if got := Perplexity(NewCorpus(), model); !math.IsInf(got, 1) {
+		t.Errorf("Perplexity(empty corpus) = %v, want +Inf", got)
+	}
+}
+
+func TestPerplexityStreamMatchesPerplexity(t *testing.T) {
+	corpus, model := buildEvalModel(t)
+
+	want := Perplexity(corpus, model)
+
+	docs := make(chan *Document, len(*corpus))
+	for _, doc := range *corpus {
+		docs <- doc
+	}
+	close(docs)
+
+	got := PerplexityStream(docs, model)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("PerplexityStream = %v, want %v (matching Perplexity)", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+package lda
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Callback is invoked once per completed iteration of SampleParallel with
+// the iteration number (0-based) and the model's current joint
+// log-likelihood, so callers can plot convergence.
+type Callback func(iteration int, logLikelihood float64)
+
+type sampleConfig struct {
+	callback Callback
+}
+
+// SampleOption configures a SampleParallel run.
+type SampleOption func(*sampleConfig)
+
+// WithCallback registers a Callback invoked after every iteration.
+func WithCallback(cb Callback) SampleOption {
+	return func(c *sampleConfig) { c.callback = cb }
+}
+
+// workerDelta accumulates one worker's word-topic assignment changes for
+// an iteration, so the hot sampling loop only reads Model's shared state
+// and never writes to it (or takes its locks) until the iteration's merge
+// step.
+type workerDelta struct {
+	wordTopic  map[int]map[int]int
+	topicTotal map[int]int
+}
+
+func newWorkerDelta() *workerDelta {
+	return &workerDelta{
+		wordTopic:  make(map[int]map[int]int),
+		topicTotal: make(map[int]int),
+	}
+}
+
+func (d *workerDelta) get(wordID, topic int) int {
+	if topics, ok := d.wordTopic[wordID]; ok {
+		return topics[topic]
+	}
+	return 0
+}
+
+func (d *workerDelta) add(wordID, topic, delta int) {
+	if d.wordTopic[wordID] == nil {
+		d.wordTopic[wordID] = make(map[int]int)
+	}
+	d.wordTopic[wordID][topic] += delta
+	d.topicTotal[topic] += delta
+}
+
+// mergeInto applies d onto model's shared word-topic table, taking only
+// the shard lock(s) for the words d actually touched.
+func (d *workerDelta) mergeInto(model *Model) {
+	for wordID, topics := range d.wordTopic {
+		model.shardMu[wordID%numModelShards].Lock()
+		for topic, delta := range topics {
+			model.wordTopics[wordID][topic] += delta
+		}
+		model.shardMu[wordID%numModelShards].Unlock()
+	}
+
+	model.topicMu.Lock()
+	for topic, delta := range d.topicTotal {
+		model.topicCounts[topic] += delta
+	}
+	model.topicMu.Unlock()
+}
+
+// SampleParallel runs an AD-LDA style approximate parallel Gibbs sampler:
+// corpus's documents are handed out through a jobQueue so workers with
+// shorter documents simply pull more of them, each worker samples against
+// a stale read of model plus its own private workerDelta, and deltas are
+// merged into model once all workers finish an iteration. model must
+// already be seeded over corpus, e.g. via NewModelFromCorpus.
+func SampleParallel(corpus *Corpus, model *Model, iterations, workers int, opts ...SampleOption) error {
+	if workers <= 0 {
+		return errors.New("SampleParallel requires workers >= 1")
+	}
+	if iterations < 0 {
+		return errors.New("SampleParallel requires iterations >= 0")
+	}
+
+	cfg := &sampleConfig{}
+	for _, apply := range opts {
+		apply(cfg)
+	}
+
+	for it := 0; it < iterations; it++ {
+		jobQueue := make(chan int, len(*corpus))
+		for i := range *corpus {
+			jobQueue <- i
+		}
+		close(jobQueue)
+
+		deltas := make([]*workerDelta, workers)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			deltas[w] = newWorkerDelta()
+			wg.Add(1)
+			go func(delta *workerDelta) {
+				defer wg.Done()
+				for docIndex := range jobQueue {
+					sampleDocument((*corpus)[docIndex], model, delta)
+				}
+			}(deltas[w])
+		}
+		wg.Wait()
+
+		for _, delta := range deltas {
+			delta.mergeInto(model)
+		}
+
+		if cfg.callback != nil {
+			cfg.callback(it, model.LogLikelihood(corpus))
+		}
+	}
+	return nil
+}
+
+// sampleDocument resamples every word occurrence in doc in place, batching
+// its topic-count changes into delta rather than model's shared state.
+func sampleDocument(doc *Document, model *Model, delta *workerDelta) {
+	iter, err := NewWordIterator(doc)
+	if err != nil {
+		return
+	}
+	for !iter.Done() {
+		resampleWord(iter, model, delta)
+		iter.Next()
+	}
+}
+
+// resampleWord draws a new topic for the word iter currently points at,
+// using collapsed Gibbs sampling over model's shared counts overlaid with
+// delta's not-yet-merged changes, and calls iter.SetTopic if it changed.
+func resampleWord(iter *WordIterator, model *Model, delta *workerDelta) {
+	word := iter.Word()
+	wordID, ok := model.wordIndex[word]
+	if !ok {
+		return // word outside the model's vocabulary
+	}
+	oldTopic := iter.Topic()
+	V := float64(len(model.vocabulary))
+
+	probs := make([]float64, model.NumTopics)
+	cumulative := 0.0
+	for k := 0; k < model.NumTopics; k++ {
+		wordTopicCount := model.wordTopics[wordID][k] + delta.get(wordID, k)
+		topicCount := model.topicCounts[k] + delta.topicTotal[k]
+		docTopicCount := iter.doc.topic_histogram[k]
+		if k == oldTopic {
+			wordTopicCount--
+			topicCount--
+			docTopicCount--
+		}
+
+		p := (float64(wordTopicCount) + model.Beta) / (float64(topicCount) + V*model.Beta) *
+			(float64(docTopicCount) + model.Alpha)
+		cumulative += p
+		probs[k] = cumulative
+	}
+	if cumulative <= 0 {
+		return
+	}
+
+	r := rand.Float64() * cumulative
+	newTopic := sort.Search(len(probs), func(i int) bool { return probs[i] >= r })
+	if newTopic >= model.NumTopics {
+		newTopic = model.NumTopics - 1
+	}
+	if newTopic == oldTopic {
+		return
+	}
+
+	iter.SetTopic(newTopic)
+	delta.add(wordID, oldTopic, -1)
+	delta.add(wordID, newTopic, 1)
+}
+
+// LogLikelihood computes the joint log-likelihood of corpus's current
+// topic assignments under model, for tracking convergence across
+// SampleParallel iterations.
+func (m *Model) LogLikelihood(corpus *Corpus) float64 {
+	var ll float64
+	for _, doc := range *corpus {
+		ll += documentLogLikelihood(doc, m)
+	}
+	return ll
+}
@@ -0,0 +1,108 @@
+package lda
+
+import "testing"
+
+func buildTestCorpus(t *testing.T, texts []string, numTopics int) *Corpus {
+	t.Helper()
+	corpus := NewCorpus()
+	for _, text := range texts {
+		doc, err := NewDocument(text, numTopics)
+		if err != nil {
+			t.Fatalf("NewDocument(%q): %v", text, err)
+		}
+		*corpus = append(*corpus, doc)
+	}
+	return corpus
+}
+
+// TestSampleParallelPreservesCounts runs several workers over several
+// iterations and checks the invariant an AD-LDA merge must hold
+// regardless of how work is interleaved: resampling only moves a word
+// occurrence from one topic to another, so the model's total assignment
+// count (summed across topics, and separately per document) right after
+// seeding must equal the same total once every worker's deltas have been
+// merged back in, no matter how the documents were split across workers.
+func TestSampleParallelPreservesCounts(t *testing.T) {
+	texts := []string{
+		"the quick brown fox jumps over the lazy dog",
+		"the dog barks at the cat in the yard",
+		"quick foxes and lazy dogs rarely meet in the wild",
+		"a cat and a dog can be friends in the yard",
+	}
+	const numTopics = 3
+
+	corpus := buildTestCorpus(t, texts, numTopics)
+	model, err := NewModelFromCorpus(corpus, numTopics)
+	if err != nil {
+		t.Fatalf("NewModelFromCorpus: %v", err)
+	}
+
+	seededTotal := 0
+	for topic := 0; topic < numTopics; topic++ {
+		seededTotal += model.TopicCount(topic)
+	}
+	seededDocSums := make([]int, len(*corpus))
+	for i, doc := range *corpus {
+		for _, count := range doc.topic_histogram {
+			seededDocSums[i] += count
+		}
+	}
+
+	const iterations = 5
+	var callbackRuns int
+	err = SampleParallel(corpus, model, iterations, 4, WithCallback(func(iteration int, ll float64) {
+		if iteration != callbackRuns {
+			t.Errorf("callback ran for iteration %d out of order (expected %d)", iteration, callbackRuns)
+		}
+		callbackRuns++
+	}))
+	if err != nil {
+		t.Fatalf("SampleParallel: %v", err)
+	}
+	if callbackRuns != iterations {
+		t.Fatalf("callback ran %d times, want %d", callbackRuns, iterations)
+	}
+
+	totalTopicCount := 0
+	for topic := 0; topic < numTopics; topic++ {
+		totalTopicCount += model.TopicCount(topic)
+	}
+	if totalTopicCount != seededTotal {
+		t.Fatalf("model topic counts sum to %d after sampling, want %d (pre-sampling total)", totalTopicCount, seededTotal)
+	}
+
+	for i, doc := range *corpus {
+		sum := 0
+		for _, count := range doc.topic_histogram {
+			sum += count
+		}
+		if sum != seededDocSums[i] {
+			t.Errorf("document %d topic_histogram sums to %d after sampling, want %d (pre-sampling sum)", i, sum, seededDocSums[i])
+		}
+	}
+}
+
+// TestSampleParallelMoreWorkersThanDocuments exercises the jobQueue with
+// more workers than documents, so most workers drain the queue and exit
+// immediately; it should neither deadlock nor panic.
+func TestSampleParallelMoreWorkersThanDocuments(t *testing.T) {
+	corpus := buildTestCorpus(t, []string{"alpha beta gamma delta"}, 2)
+	model, err := NewModelFromCorpus(corpus, 2)
+	if err != nil {
+		t.Fatalf("NewModelFromCorpus: %v", err)
+	}
+	if err := SampleParallel(corpus, model, 3, 8); err != nil {
+		t.Fatalf("SampleParallel: %v", err)
+	}
+}
+
+func TestSampleParallelRejectsZeroWorkers(t *testing.T) {
+	corpus := buildTestCorpus(t, []string{"alpha beta gamma"}, 2)
+	model, err := NewModelFromCorpus(corpus, 2)
+	if err != nil {
+		t.Fatalf("NewModelFromCorpus: %v", err)
+	}
+	if err := SampleParallel(corpus, model, 1, 0); err == nil {
+		t.Fatal("SampleParallel with 0 workers = nil error, want error")
+	}
+}
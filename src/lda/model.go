@@ -0,0 +1,132 @@
+package lda
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// numModelShards is the number of locks Model shards its word-topic table
+// across, so concurrent workers merging deltas for different words rarely
+// contend with each other.
+const numModelShards = 32
+
+// Model holds the global word-topic counts a Gibbs sampler reads and
+// updates while training LDA over a Corpus.  Word-topic counts are
+// indexed by a symbol id assigned from Vocabulary, mirroring how
+// CorpusIndex interns words for its own postings.
+type Model struct {
+	NumTopics int
+	Alpha     float64 // document-topic Dirichlet prior
+	Beta      float64 // topic-word Dirichlet prior
+
+	vocabulary  []string
+	wordIndex   map[string]int
+	wordTopics  [][]int // wordTopics[wordID][topic]
+	topicCounts []int   // topicCounts[topic], summed over all words
+
+	shardMu [numModelShards]sync.Mutex
+	topicMu sync.Mutex
+}
+
+// NewModel builds an empty Model over vocabulary with all word-topic
+// counts at zero.  Alpha and Beta are set to the common defaults
+// 50/NumTopics and 0.01; override them on the returned Model before
+// training if different priors are wanted.
+func NewModel(vocabulary []string, num_topics int) (*Model, error) {
+	if num_topics <= 1 {
+		return nil, errors.New("num_topics must be >= 2")
+	}
+
+	m := &Model{
+		NumTopics:   num_topics,
+		Alpha:       50.0 / float64(num_topics),
+		Beta:        0.01,
+		vocabulary:  append([]string(nil), vocabulary...),
+		wordIndex:   make(map[string]int, len(vocabulary)),
+		wordTopics:  make([][]int, len(vocabulary)),
+		topicCounts: make([]int, num_topics),
+	}
+	for i, w := range m.vocabulary {
+		m.wordIndex[w] = i
+		m.wordTopics[i] = make([]int, num_topics)
+	}
+	return m, nil
+}
+
+// NewModelFromCorpus collects the distinct words across corpus into a
+// Vocabulary, builds a Model over it, and seeds the model's word-topic
+// counts from each Document's current (e.g. freshly-initialized) topic
+// assignments.
+func NewModelFromCorpus(corpus *Corpus, num_topics int) (*Model, error) {
+	seen := make(map[string]struct{})
+	var vocabulary []string
+	for _, doc := range *corpus {
+		iter, err := NewWordIterator(doc)
+		if err != nil {
+			return nil, err
+		}
+		for !iter.Done() {
+			word := iter.Word()
+			if _, ok := seen[word]; !ok {
+				seen[word] = struct{}{}
+				vocabulary = append(vocabulary, word)
+			}
+			iter.Next()
+		}
+	}
+	sort.Strings(vocabulary)
+
+	model, err := NewModel(vocabulary, num_topics)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.seed(corpus); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// seed initializes wordTopics/topicCounts from corpus's current topic
+// assignments. It is not safe for concurrent use and must run before any
+// SampleParallel call touches model.
+func (m *Model) seed(corpus *Corpus) error {
+	for _, doc := range *corpus {
+		iter, err := NewWordIterator(doc)
+		if err != nil {
+			return err
+		}
+		for !iter.Done() {
+			wordID, ok := m.wordIndex[iter.Word()]
+			if !ok {
+				return errors.New("word not in model vocabulary: " + iter.Word())
+			}
+			m.wordTopics[wordID][iter.Topic()]++
+			m.topicCounts[iter.Topic()]++
+			iter.Next()
+		}
+	}
+	return nil
+}
+
+// Vocabulary returns the model's words, indexed by their symbol id.
+func (m *Model) Vocabulary() []string {
+	return m.vocabulary
+}
+
+// WordTopicCount returns how many times word has been assigned topic
+// across the whole corpus.  It returns 0 if word is outside the model's
+// vocabulary.
+func (m *Model) WordTopicCount(word string, topic int) int {
+	wordID, ok := m.wordIndex[word]
+	if !ok {
+		return 0
+	}
+	return m.wordTopics[wordID][topic]
+}
+
+// TopicCount returns the total number of word occurrences assigned to
+// topic across the whole corpus.
+func (m *Model) TopicCount(topic int) int {
+	return m.topicCounts[topic]
+}
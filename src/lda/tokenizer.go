@@ -0,0 +1,122 @@
+package lda
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits a document's raw text into words.  NewDocument and
+// LoadCorpus/StreamCorpus accept one via WithTokenizer; the default,
+// WhitespaceTokenizer, reproduces the package's original regex-based
+// behavior.
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// WhitespaceTokenizer lower-cases text, strips the symbols SymbolsRegexp
+// matches, and splits on whitespace. This is the tokenizer NewDocument has
+// always used, and remains the default.
+type WhitespaceTokenizer struct{}
+
+func (WhitespaceTokenizer) Tokenize(text string) []string {
+	return strings.Fields(strings.ToLower(RemoveTailingSymbols(text)))
+}
+
+// UnicodeWordTokenizer splits text into maximal runs of letters and
+// numbers, per unicode.IsLetter/unicode.IsNumber, lower-cased via
+// unicode.ToLower. Unlike WhitespaceTokenizer and SymbolsRegexp, which
+// only strip a fixed set of ASCII punctuation, this correctly drops any
+// Unicode punctuation or symbol (e.g. "—", "。", "，") without needing it
+// enumerated, and keeps CJK and accented letters. It does not segment
+// text written without spaces: an unbroken run of CJK letters is returned
+// as a single token.
+type UnicodeWordTokenizer struct{}
+
+func (UnicodeWordTokenizer) Tokenize(text string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			current = append(current, unicode.ToLower(r))
+		} else {
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// NGramTokenizer produces overlapping character n-grams of length N from
+// the lower-cased, whitespace-stripped text. It is tokenizer-agnostic
+// about word boundaries, which makes it useful for scripts where
+// UnicodeWordTokenizer would otherwise merge an entire clause into one
+// run.
+type NGramTokenizer struct {
+	N int
+}
+
+func NewNGramTokenizer(n int) NGramTokenizer {
+	if n <= 0 {
+		panic("NGramTokenizer requires n >= 1")
+	}
+	return NGramTokenizer{N: n}
+}
+
+func (t NGramTokenizer) Tokenize(text string) []string {
+	runes := make([]rune, 0, len(text))
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsSpace(r) {
+			runes = append(runes, r)
+		}
+	}
+	if len(runes) < t.N {
+		return nil
+	}
+	grams := make([]string, 0, len(runes)-t.N+1)
+	for i := 0; i+t.N <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+t.N]))
+	}
+	return grams
+}
+
+// StemFunc reduces a single token to its stem.
+type StemFunc func(word string) string
+
+// StemmingTokenizer wraps another Tokenizer and applies Stem to every
+// token it produces.
+type StemmingTokenizer struct {
+	Base Tokenizer
+	Stem StemFunc
+}
+
+func NewStemmingTokenizer(base Tokenizer, stem StemFunc) StemmingTokenizer {
+	return StemmingTokenizer{Base: base, Stem: stem}
+}
+
+func (t StemmingTokenizer) Tokenize(text string) []string {
+	words := t.Base.Tokenize(text)
+	stemmed := make([]string, len(words))
+	for i, w := range words {
+		stemmed[i] = t.Stem(w)
+	}
+	return stemmed
+}
+
+// SimpleEnglishStem strips a handful of common English suffixes ("ing",
+// "edly", "ed", "es", "s"). It is a naive suffix-stripper, not a Porter
+// stemmer, intended as a StemFunc default for StemmingTokenizer when
+// nothing more precise is needed.
+func SimpleEnglishStem(word string) string {
+	for _, suffix := range []string{"edly", "ing", "ed", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word) > len(suffix)+2 {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
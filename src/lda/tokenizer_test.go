@@ -0,0 +1,68 @@
+package lda
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWhitespaceTokenizer(t *testing.T) {
+	got := WhitespaceTokenizer{}.Tokenize("The Quick, brown fox: jumps!")
+	want := []string{"the", "quick", "brown", "fox", "jumps"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestUnicodeWordTokenizer(t *testing.T) {
+	got := UnicodeWordTokenizer{}.Tokenize("Hello, 世界！ foo-bar42")
+	want := []string{"hello", "世界", "foo", "bar42"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestNGramTokenizer(t *testing.T) {
+	got := NewNGramTokenizer(3).Tokenize("ABCD")
+	want := []string{"abc", "bcd"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %v, want %v", got, want)
+	}
+
+	if got := NewNGramTokenizer(3).Tokenize("ab"); got != nil {
+		t.Errorf("Tokenize(short text) = %v, want nil", got)
+	}
+}
+
+func TestNGramTokenizerPanicsOnNonPositiveN(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewNGramTokenizer(0) did not panic")
+		}
+	}()
+	NewNGramTokenizer(0)
+}
+
+func TestStemmingTokenizer(t *testing.T) {
+	tok := NewStemmingTokenizer(WhitespaceTokenizer{}, SimpleEnglishStem)
+	got := tok.Tokenize("jumping jumps jumped foxes")
+	want := []string{"jump", "jump", "jump", "fox"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestSimpleEnglishStem(t *testing.T) {
+	cases := map[string]string{
+		"jumping": "jump",
+		"jumps":   "jump",
+		"jumped":  "jump",
+		"foxes":   "fox",
+		"cats":    "cat",
+		"is":      "is", // too short to strip "s" safely
+	}
+	for in, want := range cases {
+		if got := SimpleEnglishStem(in); got != want {
+			t.Errorf("SimpleEnglishStem(%q) = %q, want %q", in, got, want)
+		}
+	}
+}